@@ -0,0 +1,65 @@
+package jl
+
+import "testing"
+
+func TestAlignLeft(t *testing.T) {
+	ctx := &Context{}
+	got := Align{Width: 6, How: AlignLeft, Fill: '.'}.Transform(ctx, "ab")
+	want := "ab...."
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestAlignRight(t *testing.T) {
+	ctx := &Context{}
+	got := Align{Width: 6, How: AlignRight, Fill: '.'}.Transform(ctx, "ab")
+	want := "....ab"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestAlignCenterSplitsExtraWithOddCellOnTheRight(t *testing.T) {
+	ctx := &Context{}
+	got := Align{Width: 5, How: AlignCenter, Fill: '.'}.Transform(ctx, "ab")
+	want := ".ab.."
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestAlignDefaultFillIsSpace(t *testing.T) {
+	ctx := &Context{}
+	got := Align{Width: 5}.Transform(ctx, "ab")
+	want := "ab   "
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestAlignInputAlreadyAtWidthIsUnchanged(t *testing.T) {
+	ctx := &Context{}
+	got := Align{Width: 2, How: AlignCenter, Fill: '.'}.Transform(ctx, "ab")
+	if got != "ab" {
+		t.Errorf("Transform() = %q, want %q", got, "ab")
+	}
+}
+
+func TestLeftPadDelegatesToAlignRight(t *testing.T) {
+	ctx := &Context{}
+	got := LeftPad(6).Transform(ctx, "ab")
+	want := "    ab"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestRightPadDelegatesToAlignLeft(t *testing.T) {
+	ctx := &Context{}
+	got := RightPad(6).Transform(ctx, "ab")
+	want := "ab    "
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
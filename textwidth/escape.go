@@ -0,0 +1,96 @@
+package textwidth
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Reset is the SGR sequence that clears all active text attributes.
+const Reset = "\x1b[0m"
+
+const (
+	escNone = iota
+	escCSI  // "\x1b[...m" (SGR)
+	escOSC  // "\x1b]...\x07" or "\x1b]...\x1b\\"
+	escG0   // "\x1b(B" charset select
+)
+
+// EscapeState tracks ANSI escape sequences consumed rune-by-rune across a string so that a cut or line-break point
+// can re-emit a reset followed by whatever SGR sequences were still active, without ever splitting an escape
+// sequence itself or counting it as width. The zero value is ready to use.
+type EscapeState struct {
+	kind int
+	buf  strings.Builder // raw bytes of the sequence currently being consumed
+	sgr  []string        // raw SGR sequences applied since the last reset, in order
+}
+
+// Consume feeds the next rune of the input into the state machine. It returns true if r belongs to an escape
+// sequence (including the leading ESC) and should not be counted towards visible width or emitted standalone.
+func (s *EscapeState) Consume(r rune) bool {
+	if s.kind == escNone {
+		if r != '\x1b' {
+			return false
+		}
+		s.kind = escCSI // provisional, refined once we see the next rune
+		s.buf.Reset()
+		s.buf.WriteRune(r)
+		return true
+	}
+
+	s.buf.WriteRune(r)
+	if s.buf.Len() == 1+utf8.RuneLen(r) { // r is the rune right after ESC
+		switch r {
+		case '[':
+			s.kind = escCSI
+		case ']':
+			s.kind = escOSC
+		case '(':
+			s.kind = escG0
+		default:
+			// Not a sequence we track; treat as a bare ESC already consumed.
+			s.kind = escNone
+		}
+		return true
+	}
+
+	switch s.kind {
+	case escCSI:
+		if r >= 0x40 && r <= 0x7E { // CSI final byte
+			s.finishCSI(r)
+			s.kind = escNone
+		}
+	case escOSC:
+		if r == '\x07' {
+			s.kind = escNone
+		} else if r == '\\' && strings.HasSuffix(s.buf.String(), "\x1b\\") {
+			s.kind = escNone
+		}
+	case escG0:
+		s.kind = escNone
+	}
+	return true
+}
+
+func (s *EscapeState) finishCSI(final rune) {
+	if final != 'm' {
+		return
+	}
+	seq := s.buf.String()
+	params := strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b["), "m")
+	if params == "" || params == "0" {
+		s.sgr = nil
+		return
+	}
+	s.sgr = append(s.sgr, seq)
+}
+
+// Open reports whether any SGR attribute is currently active.
+func (s *EscapeState) Open() bool {
+	return len(s.sgr) > 0
+}
+
+// Reopen returns the escape sequences needed to restore the currently active SGR attributes, for re-emission after
+// a reset at a splice or line-break point.
+func (s *EscapeState) Reopen() string {
+	return strings.Join(s.sgr, "")
+}
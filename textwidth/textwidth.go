@@ -0,0 +1,106 @@
+// Package textwidth provides ANSI-escape- and East-Asian-width-aware helpers for
+// measuring and slicing terminal strings. Transformers that need to reason about
+// on-screen column width (truncation, padding, wrapping, alignment) should go
+// through VisibleWidth and SliceByWidth rather than utf8.RuneCountInString and raw
+// byte slicing, so that embedded SGR/OSC escape sequences are never counted as
+// visible cells and are never left dangling by a cut.
+package textwidth
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// isASCII reports whether s contains only plain (non-escape) single-byte (7-bit) runes, in which case byte length,
+// rune count and cell width all coincide and the slow path can be skipped. A string containing ESC is rejected even
+// though ESC itself is 7-bit, since it may start an ANSI escape sequence that must not be counted as visible width.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] > 0x7F || s[i] == 0x1B {
+			return false
+		}
+	}
+	return true
+}
+
+// RuneWidth returns the number of terminal cells a single visible (non-escape) rune occupies. Callers that already
+// know r isn't part of an escape sequence (e.g. because an EscapeState.Consume call returned false for it) can use
+// this to accumulate width incrementally instead of re-measuring a whole string with VisibleWidth.
+func RuneWidth(r rune) int {
+	return runewidth.RuneWidth(r)
+}
+
+// VisibleWidth returns the number of terminal cells s would occupy once ANSI escape
+// sequences (SGR, OSC, and the "\x1b(B" charset-select sequence) are stripped and
+// East-Asian wide characters are accounted for.
+func VisibleWidth(s string) int {
+	if isASCII(s) {
+		return len(s)
+	}
+	width := 0
+	st := EscapeState{}
+	for _, r := range s {
+		if st.Consume(r) {
+			continue
+		}
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// SliceByWidth returns the substring of s spanning visible columns [start, end),
+// preserving any ANSI escape sequences that were open at the cut points by re-opening
+// the sequences that were active at start before the slice and emitting a reset after
+// it if any are still open.
+func SliceByWidth(s string, start, end int) string {
+	if isASCII(s) {
+		if start < 0 {
+			start = 0
+		}
+		if end > len(s) {
+			end = len(s)
+		}
+		if start >= end {
+			return ""
+		}
+		return s[start:end]
+	}
+
+	var out strings.Builder
+	st := EscapeState{}
+	col := 0
+	reopened := false
+	for _, r := range s {
+		if st.Consume(r) {
+			if col >= start && col < end {
+				out.WriteRune(r)
+			}
+			continue
+		}
+		w := RuneWidth(r)
+		if col+w > end {
+			// A wide rune straddling end doesn't fit; stop rather than overshoot the requested width.
+			break
+		}
+		if col >= start {
+			// Only re-open at the cut: if start is 0, any leading escape sequence was already copied through
+			// verbatim above, so emitting it again here would duplicate it.
+			if !reopened && start > 0 {
+				out.WriteString(st.Reopen())
+			}
+			reopened = true
+			out.WriteRune(r)
+		}
+		col += w
+		if col >= end {
+			// Stop entirely at the cut: any escape sequence past this point (e.g. a trailing reset) must not
+			// affect st's open/closed bookkeeping, since it never gets emitted.
+			break
+		}
+	}
+	if st.Open() {
+		out.WriteString(Reset)
+	}
+	return out.String()
+}
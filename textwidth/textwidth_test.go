@@ -0,0 +1,63 @@
+package textwidth
+
+import "testing"
+
+func TestVisibleWidth(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"plain ascii", "hello", 5},
+		{"ansi colored ascii", "\x1b[31mhello\x1b[0m", 5},
+		{"osc hyperlink", "\x1b]8;;http://example.com\x07hello\x1b]8;;\x07", 5},
+		{"cjk wide runes", "你好", 4},
+		{"ansi colored cjk", "\x1b[31m你好\x1b[0m", 4},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := VisibleWidth(c.in); got != c.want {
+				t.Errorf("VisibleWidth(%q) = %d, want %d", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSliceByWidthPlain(t *testing.T) {
+	if got := SliceByWidth("hello world", 0, 5); got != "hello" {
+		t.Errorf("SliceByWidth = %q, want %q", got, "hello")
+	}
+}
+
+func TestSliceByWidthStopsBeforeOverflowingWideRune(t *testing.T) {
+	// "你好" is 4 cells wide; asking for 3 must not include the second (2-cell) glyph.
+	got := SliceByWidth("你好", 0, 3)
+	if want := "你"; got != want {
+		t.Errorf("SliceByWidth(%q, 0, 3) = %q, want %q", "你好", got, want)
+	}
+}
+
+func TestSliceByWidthPreservesEscapesAcrossCut(t *testing.T) {
+	got := SliceByWidth("\x1b[31mhello world\x1b[0m", 0, 5)
+	want := "\x1b[31mhello" + Reset
+	if got != want {
+		t.Errorf("SliceByWidth = %q, want %q", got, want)
+	}
+}
+
+func TestSliceByWidthReopensEscapeDroppedBeforeStart(t *testing.T) {
+	got := SliceByWidth("\x1b[31mhello world\x1b[0m", 6, 11)
+	want := "\x1b[31mworld" + Reset
+	if got != want {
+		t.Errorf("SliceByWidth = %q, want %q", got, want)
+	}
+}
+
+func TestRuneWidth(t *testing.T) {
+	if RuneWidth('a') != 1 {
+		t.Errorf("RuneWidth('a') = %d, want 1", RuneWidth('a'))
+	}
+	if RuneWidth('你') != 2 {
+		t.Errorf("RuneWidth('你') = %d, want 2", RuneWidth('你'))
+	}
+}
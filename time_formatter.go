@@ -0,0 +1,95 @@
+package jl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+// TimeFormatter parses ctx.Original against Layouts, trying each in order, and renders the result either as an
+// absolute timestamp (formatted with whichever layout matched) or, when Relative is true, as a humanized offset
+// such as "12ms ago" or "in 2m".
+type TimeFormatter struct {
+	// Layouts is tried in order. Each entry is either a Go reference layout (e.g. time.RFC3339) passed to
+	// time.ParseInLocation with time.Local, or the sentinel "auto" which falls back to dateparse.ParseLocal. The first layout to parse
+	// ctx.Original successfully wins; when it was a concrete layout, that same layout is reused to format the
+	// absolute output, keeping parse and format symmetric. A nil or empty Layouts behaves as []string{"auto"}.
+	Layouts []string
+	// Relative renders the parsed time as a humanized offset from RelativeTo() instead of an absolute timestamp;
+	// an offset of a day or more is rendered as the date alone (2006-01-02).
+	Relative bool
+	// RelativeTo returns the instant offsets are computed against. Defaults to time.Now when nil.
+	RelativeTo func() time.Time
+}
+
+const autoLayout = "auto"
+
+func (t TimeFormatter) Transform(ctx *Context, input string) string {
+	layouts := t.Layouts
+	if len(layouts) == 0 {
+		layouts = []string{autoLayout}
+	}
+
+	var (
+		date   time.Time
+		err    error = fmt.Errorf("no layouts configured")
+		format       = time.RFC3339
+	)
+	for _, layout := range layouts {
+		if layout == autoLayout {
+			date, err = dateparse.ParseLocal(ctx.Original)
+			format = time.RFC3339
+		} else {
+			date, err = time.ParseInLocation(layout, ctx.Original, time.Local)
+			format = layout
+		}
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return input
+	}
+	date = date.In(time.Local)
+
+	if t.Relative {
+		return formatRelative(date, t.relativeTo())
+	}
+	return date.Format(format)
+}
+
+func (t TimeFormatter) relativeTo() time.Time {
+	if t.RelativeTo != nil {
+		return t.RelativeTo()
+	}
+	return time.Now()
+}
+
+// formatRelative renders the offset between date and now as a humanized "X ago" / "in X" string, crossing
+// millisecond/second/minute/hour/day boundaries the same way typical time-ago helpers do.
+func formatRelative(date, now time.Time) string {
+	d := now.Sub(date)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var magnitude string
+	switch {
+	case d < time.Second:
+		magnitude = fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		magnitude = fmt.Sprintf("%.1fs", d.Seconds())
+	case d < time.Hour:
+		magnitude = fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		magnitude = fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return date.Format("2006-01-02")
+	}
+	if future {
+		return "in " + magnitude
+	}
+	return magnitude + " ago"
+}
@@ -0,0 +1,54 @@
+package jl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatRelative(t *testing.T) {
+	now := time.Date(2026, 7, 27, 12, 0, 0, 0, time.Local)
+	cases := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{"milliseconds ago", now.Add(-12 * time.Millisecond), "12ms ago"},
+		{"seconds ago", now.Add(-3400 * time.Millisecond), "3.4s ago"},
+		{"minutes ago", now.Add(-2 * time.Minute), "2m ago"},
+		{"hours and minutes ago", now.Add(-90 * time.Minute), "1h30m ago"},
+		{"in the future", now.Add(2 * time.Minute), "in 2m"},
+		{"beyond a day falls back to a date", now.Add(-48 * time.Hour), "2026-07-25"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := formatRelative(c.date, now); got != c.want {
+				t.Errorf("formatRelative() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTimeFormatterFallsBackThroughLayouts(t *testing.T) {
+	ctx := &Context{Original: "2026-07-27"}
+	tf := TimeFormatter{Layouts: []string{time.RFC3339, "2006-01-02"}}
+	got := tf.Transform(ctx, "")
+	want := "2026-07-27"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestTimeFormatterRelative(t *testing.T) {
+	fixedNow := time.Date(2026, 7, 27, 12, 0, 0, 0, time.UTC)
+	ctx := &Context{Original: "2026-07-27T11:58:00Z"}
+	tf := TimeFormatter{
+		Layouts:    []string{autoLayout},
+		Relative:   true,
+		RelativeTo: func() time.Time { return fixedNow },
+	}
+	got := tf.Transform(ctx, "")
+	want := "2m ago"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
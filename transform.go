@@ -1,13 +1,10 @@
 package jl
 
 import (
-	"bytes"
 	"fmt"
-	"github.com/araddon/dateparse"
+	"github.com/nimatrueway/jl/textwidth"
 	"regexp"
 	"strings"
-	"time"
-	"unicode/utf8"
 )
 
 // Context provides the current transformation context, to be used by Transformers and Stringers.
@@ -46,29 +43,63 @@ func (t Truncate) Transform(ctx *Context, input string) string {
 	if ctx.DisableTruncate {
 		return input
 	}
-	if utf8.RuneCountInString(input) <= int(t) {
+	if textwidth.VisibleWidth(input) <= int(t) {
 		return input
 	}
-	return input[:t]
+	return textwidth.SliceByWidth(input, 0, int(t))
 }
 
-// Ellipsize replaces characters in the middle of the string with a single "…" character so that it fits within the
-// requested length.
-type Ellipsize int
+// EllipsisPosition selects where Ellipsize cuts the input relative to the marker.
+type EllipsisPosition int
 
-func (remain Ellipsize) Transform(ctx *Context, input string) string {
+const (
+	// EllipsisMiddle drops characters from the middle of the string, keeping both ends.
+	EllipsisMiddle EllipsisPosition = iota
+	// EllipsisHead drops characters from the start of the string, keeping the tail.
+	EllipsisHead
+	// EllipsisTail drops characters from the end of the string, keeping the head.
+	EllipsisTail
+)
+
+// Ellipsize replaces the dropped portion of an overlong string with Marker so that it fits within Width, cutting at
+// the position requested by Where. If Marker is empty, the overflow is dropped silently with nothing put in its
+// place.
+type Ellipsize struct {
+	Width  int
+	Marker string
+	Where  EllipsisPosition
+}
+
+// NewEllipsize returns an Ellipsize that truncates in the middle with the classic single "…" marker.
+func NewEllipsize(width int) Ellipsize {
+	return Ellipsize{Width: width, Marker: "…", Where: EllipsisMiddle}
+}
+
+func (e Ellipsize) Transform(ctx *Context, input string) string {
 	if ctx.DisableTruncate {
 		return input
 	}
-	length := utf8.RuneCountInString(input)
-	if length <= int(remain) {
+	length := textwidth.VisibleWidth(input)
+	if length <= e.Width {
 		return input
 	}
-	remain -= 1 // account for the ellipsis
-	chomped := length - int(remain)
-	start := int(remain) / 2
-	end := start + chomped
-	return input[:start] + "…" + input[end:]
+	markerWidth := textwidth.VisibleWidth(e.Marker)
+	avail := e.Width - markerWidth
+	if avail < 0 {
+		avail = 0
+	}
+	switch e.Where {
+	case EllipsisHead:
+		start := length - avail
+		return e.Marker + textwidth.SliceByWidth(input, start, length)
+	case EllipsisTail:
+		return textwidth.SliceByWidth(input, 0, avail) + e.Marker
+	default:
+		startLen := avail / 2
+		endLen := avail - startLen
+		end := length - endLen
+		return textwidth.SliceByWidth(input, 0, startLen) + e.Marker + textwidth.SliceByWidth(input, end, length)
+	}
 }
 
 // PackageFold
@@ -78,7 +109,7 @@ func (cap JvmClassPathFold) Transform(ctx *Context, input string) string {
 	if ctx.DisableTruncate {
 		return input
 	}
-	length := utf8.RuneCountInString(input)
+	length := textwidth.VisibleWidth(input)
 	if length <= int(cap) {
 		return input
 	}
@@ -88,75 +119,100 @@ func (cap JvmClassPathFold) Transform(ctx *Context, input string) string {
 	className := parts[len(parts)-1]
 	doCompact := false
 	// class name
-	if len(className) <= remaining {
+	if textwidth.VisibleWidth(className) <= remaining {
 		output = className
-		remaining -= len(output)
+		remaining -= textwidth.VisibleWidth(output)
 	} else {
 		classNameParts := strings.Split(regexp.MustCompile("(.)([A-Z]|(?:\\$+))").ReplaceAllString(className, "${1}_${2}"), "_")
 		for i, v := range classNameParts {
 			remainingUpperLetters := len(classNameParts) - i - 1
-			if doCompact == false && (len(v)+remainingUpperLetters) > remaining {
+			if doCompact == false && (textwidth.VisibleWidth(v)+remainingUpperLetters) > remaining {
 				doCompact = true
 			}
 			if doCompact {
 				cut := remaining - remainingUpperLetters
-				output += v[:cut+1]
+				output += textwidth.SliceByWidth(v, 0, cut+1)
 				remaining -= cut
 			} else {
 				output += v
-				remaining -= len(v)
+				remaining -= textwidth.VisibleWidth(v)
 			}
 		}
 	}
 	// packages
 	for i := len(parts) - 2; i >= 0; i-- {
-		if doCompact == false && (i*2)+1+len(parts[i]) >= remaining {
+		if doCompact == false && (i*2)+1+textwidth.VisibleWidth(parts[i]) >= remaining {
 			doCompact = true
 		}
 		if doCompact {
 			if remaining > 1 {
-				output = string(parts[i][0]) + "." + output
+				output = textwidth.SliceByWidth(parts[i], 0, 1) + "." + output
 				remaining -= 2
 			}
 		} else {
 			output = parts[i] + "." + output
-			remaining -= len(parts[i])
+			remaining -= textwidth.VisibleWidth(parts[i])
 			remaining -= 1
 		}
 	}
 	return output
 }
 
-// LeftPad pads the left side of the string with spaces so that the string becomes the requested length.
-type LeftPad int
+// Alignment selects where Align places the padding relative to the input.
+type Alignment int
 
-func (t LeftPad) Transform(ctx *Context, input string) string {
-	spaces := int(t) - utf8.RuneCountInString(input)
-	if spaces <= 0 {
+const (
+	// AlignLeft pads the right side, keeping the input flush with the left edge.
+	AlignLeft Alignment = iota
+	// AlignRight pads the left side, keeping the input flush with the right edge.
+	AlignRight
+	// AlignCenter splits the padding between both sides, with any odd cell going to the right.
+	AlignCenter
+)
+
+// Align pads the string with Fill cells so that it becomes Width visible cells wide, placing the original content
+// according to How. All width math goes through the textwidth helpers so it composes correctly with colored input
+// and wide characters. Fill is repeated once per missing cell, so a Fill rune wider than one cell makes the result
+// wider than Width.
+type Align struct {
+	Width int
+	How   Alignment
+	Fill  rune
+}
+
+func (a Align) Transform(ctx *Context, input string) string {
+	extra := a.Width - textwidth.VisibleWidth(input)
+	if extra <= 0 {
 		return input
 	}
-	buf := bytes.NewBuffer(make([]byte, 0, spaces+len(input)))
-	for i := 0; i < spaces; i++ {
-		buf.WriteRune(' ')
+	fill := a.Fill
+	if fill == 0 {
+		fill = ' '
 	}
-	buf.WriteString(input)
-	return buf.String()
+	switch a.How {
+	case AlignRight:
+		return strings.Repeat(string(fill), extra) + input
+	case AlignCenter:
+		left := extra / 2
+		right := extra - left
+		return strings.Repeat(string(fill), left) + input + strings.Repeat(string(fill), right)
+	default: // AlignLeft
+		return input + strings.Repeat(string(fill), extra)
+	}
+}
+
+// LeftPad pads the left side of the string with spaces so that the string becomes the requested length.
+type LeftPad int
+
+func (t LeftPad) Transform(ctx *Context, input string) string {
+	return Align{Width: int(t), How: AlignRight, Fill: ' '}.Transform(ctx, input)
 }
 
 // LeftPad pads the right side of the string with spaces so that the string becomes the requested length.
 type RightPad int
 
 func (t RightPad) Transform(ctx *Context, input string) string {
-	pad := int(t) - utf8.RuneCountInString(input)
-	if pad <= 0 {
-		return input
-	}
-	buf := bytes.NewBuffer(make([]byte, 0, pad+len(input)))
-	buf.WriteString(input)
-	for i := 0; i < pad; i++ {
-		buf.WriteRune(' ')
-	}
-	return buf.String()
+	return Align{Width: int(t), How: AlignLeft, Fill: ' '}.Transform(ctx, input)
 }
 
 // Format calls fmt.Sprintf() with the requested format string.
@@ -165,13 +221,3 @@ type Format string
 func (t Format) Transform(ctx *Context, input string) string {
 	return fmt.Sprintf(string(t), input)
 }
-
-type TimeFormatter string
-
-func (t TimeFormatter) Transform(ctx *Context, input string) string {
-	date, err := dateparse.ParseLocal(ctx.Original)
-	if err != nil {
-		return input
-	}
-	return date.In(time.Local).Format(string(t))
-}
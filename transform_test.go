@@ -0,0 +1,65 @@
+package jl
+
+import "testing"
+
+func TestEllipsizeMiddle(t *testing.T) {
+	ctx := &Context{}
+	got := Ellipsize{Width: 6, Marker: "…", Where: EllipsisMiddle}.Transform(ctx, "helloworld")
+	want := "he…rld"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestEllipsizeHead(t *testing.T) {
+	ctx := &Context{}
+	got := Ellipsize{Width: 6, Marker: "…", Where: EllipsisHead}.Transform(ctx, "helloworld")
+	want := "…world"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestEllipsizeTail(t *testing.T) {
+	ctx := &Context{}
+	got := Ellipsize{Width: 6, Marker: "…", Where: EllipsisTail}.Transform(ctx, "helloworld")
+	want := "hello…"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestEllipsizeMultiRuneMarkerSubtractsItsOwnWidth(t *testing.T) {
+	ctx := &Context{}
+	got := Ellipsize{Width: 6, Marker: "..", Where: EllipsisMiddle}.Transform(ctx, "helloworld")
+	want := "he..ld"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestEllipsizeEmptyMarkerDropsOverflowSilently(t *testing.T) {
+	ctx := &Context{}
+	got := Ellipsize{Width: 5, Marker: "", Where: EllipsisTail}.Transform(ctx, "helloworld")
+	want := "hello"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestEllipsizeShortInputUnchanged(t *testing.T) {
+	ctx := &Context{}
+	got := Ellipsize{Width: 20, Marker: "…", Where: EllipsisMiddle}.Transform(ctx, "short")
+	if got != "short" {
+		t.Errorf("Transform() = %q, want %q", got, "short")
+	}
+}
+
+func TestNewEllipsizeMatchesMiddleModeDefaults(t *testing.T) {
+	ctx := &Context{}
+	got := NewEllipsize(6).Transform(ctx, "helloworld")
+	want := "he…rld"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
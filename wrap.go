@@ -0,0 +1,105 @@
+package jl
+
+import (
+	"strings"
+
+	"github.com/nimatrueway/jl/textwidth"
+)
+
+// Wrap reflows the input across multiple lines so that no line exceeds Width visible cells, not counting the Indent
+// inserted at the start of every wrapped line. Existing "\n" characters in the input are preserved as forced line breaks. If
+// BreakWords is true, a single token that itself exceeds Width is hard-broken on a cell boundary; otherwise it is
+// left on its own (overlong) line. Any SGR sequence still open at a wrap point is reset at the end of the line and
+// reopened at the start of the next one, so colors don't bleed across the break.
+type Wrap struct {
+	Width      int
+	Indent     string
+	BreakWords bool
+}
+
+func (w Wrap) Transform(ctx *Context, input string) string {
+	if ctx.DisableTruncate || w.Width <= 0 {
+		return input
+	}
+
+	var out strings.Builder
+	st := textwidth.EscapeState{}
+	lineWidth := 0
+	var token strings.Builder
+	tokenWidth := 0
+	tokenStart := "" // SGR sequences active before the current token's first rune
+
+	newline := func(state *textwidth.EscapeState) {
+		if state.Open() {
+			out.WriteString(textwidth.Reset)
+		}
+		out.WriteString("\n")
+		out.WriteString(w.Indent)
+		if state.Open() {
+			out.WriteString(state.Reopen())
+		}
+		lineWidth = 0
+	}
+
+	flushToken := func() {
+		if token.Len() == 0 {
+			return
+		}
+		// Seed local with the SGR state carried over from earlier tokens (tokenStart), so a wrap or hard break
+		// doesn't lose color that this token never itself opened or closed.
+		local := textwidth.EscapeState{}
+		for _, r := range tokenStart {
+			local.Consume(r)
+		}
+		if lineWidth > 0 && lineWidth+tokenWidth > w.Width {
+			newline(&local)
+		}
+		if w.BreakWords && tokenWidth > w.Width {
+			for _, r := range token.String() {
+				if local.Consume(r) {
+					out.WriteRune(r)
+					continue
+				}
+				rw := textwidth.RuneWidth(r)
+				if lineWidth > 0 && lineWidth+rw > w.Width {
+					newline(&local)
+				}
+				out.WriteRune(r)
+				lineWidth += rw
+			}
+		} else {
+			out.WriteString(token.String())
+			lineWidth += tokenWidth
+		}
+		token.Reset()
+		tokenWidth = 0
+	}
+
+	for _, r := range input {
+		if token.Len() == 0 {
+			tokenStart = st.Reopen()
+		}
+		if st.Consume(r) {
+			token.WriteRune(r)
+			continue
+		}
+		if r == '\n' {
+			flushToken()
+			newline(&st)
+			continue
+		}
+		if r == ' ' || r == '\t' {
+			flushToken()
+			if lineWidth > 0 {
+				out.WriteRune(r)
+				lineWidth += textwidth.RuneWidth(r)
+			}
+			continue
+		}
+		token.WriteRune(r)
+		tokenWidth += textwidth.RuneWidth(r)
+	}
+	flushToken()
+
+	return out.String()
+}
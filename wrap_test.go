@@ -0,0 +1,39 @@
+package jl
+
+import "testing"
+
+func TestWrapBreaksOnWideRuneWidth(t *testing.T) {
+	ctx := &Context{}
+	got := Wrap{Width: 8}.Transform(ctx, "你好 你好")
+	want := "你好 \n你好"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapHardBreakResetsAndReopensColorAtMidTokenBreak(t *testing.T) {
+	ctx := &Context{}
+	got := Wrap{Width: 4, BreakWords: true, Indent: ">>"}.Transform(ctx, "\x1b[31mAAAABBBB\x1b[0m")
+	want := "\x1b[31mAAAA\x1b[0m\n>>\x1b[31mBBBB\x1b[0m"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapHardBreakCarriesOverColorFromEarlierToken(t *testing.T) {
+	ctx := &Context{}
+	got := Wrap{Width: 6, BreakWords: true, Indent: ">>"}.Transform(ctx, "\x1b[31mhi abcdefghijklmnop")
+	want := "\x1b[31mhi \x1b[0m\n>>\x1b[31mabcdef\x1b[0m\n>>\x1b[31mghijkl\x1b[0m\n>>\x1b[31mmnop"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapPreservesForcedNewlines(t *testing.T) {
+	ctx := &Context{}
+	got := Wrap{Width: 80}.Transform(ctx, "line one\nline two")
+	want := "line one\nline two"
+	if got != want {
+		t.Errorf("Transform() = %q, want %q", got, want)
+	}
+}